@@ -58,7 +58,10 @@ func init() {
 
 	// the list of kinds that are scoped at the root of the api hierarchy
 	// if a kind is not enumerated here, it is assumed to have a namespace scope
-	rootScoped := sets.NewString()
+	//
+	// ImageMetrics is root-scoped because Image itself is cluster-scoped; ImageStreamMetrics is
+	// left namespace-scoped to match the namespaced ImageStream it reports on.
+	rootScoped := sets.NewString("ImageMetrics")
 
 	ignoredKinds := sets.NewString()
 
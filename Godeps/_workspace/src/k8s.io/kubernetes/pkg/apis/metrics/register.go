@@ -0,0 +1,39 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// GroupName is the API group ImageMetrics and ImageStreamMetrics are served under.
+const GroupName = "metrics"
+
+// SchemeGroupVersion is the internal, unversioned group used when registering ImageMetrics and
+// ImageStreamMetrics with api.Scheme. install/install.go registers the external version(s) a
+// client actually requests and decodes against.
+var SchemeGroupVersion = unversioned.GroupVersion{Group: GroupName, Version: ""}
+
+func init() {
+	api.Scheme.AddKnownTypes(SchemeGroupVersion,
+		&ImageMetrics{},
+		&ImageMetricsList{},
+		&ImageStreamMetrics{},
+		&ImageStreamMetricsList{},
+	)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics is the internal version of the metrics API group. OpenShift extends it with
+// ImageMetrics and ImageStreamMetrics so that image storage/traffic counters can be served,
+// encoded, and versioned the same way as any other API type instead of as ad hoc subresources.
+package metrics
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// ImageMetrics reports storage and traffic counters for a single Image.
+type ImageMetrics struct {
+	kapi.TypeMeta
+	kapi.ObjectMeta
+
+	// LayerBytesStored is the sum of the size of every layer referenced by the image, including
+	// layers shared with other images.
+	LayerBytesStored int64
+	// UniqueBlobBytes is the portion of LayerBytesStored not shared with any other Image, i.e.
+	// what would be reclaimed if this Image were pruned.
+	UniqueBlobBytes int64
+	// PullsPerMinute is a decaying rate of pull requests for this image's manifest or any of its
+	// layers.
+	PullsPerMinute float64
+	// PushesPerMinute is a decaying rate of pushes of this exact manifest.
+	PushesPerMinute float64
+	// LastPullTime is the time of the most recent pull of this image, if any.
+	LastPullTime *unversioned.Time
+}
+
+// ImageMetricsList is a list of ImageMetrics.
+type ImageMetricsList struct {
+	kapi.TypeMeta
+	kapi.ListMeta
+
+	Items []ImageMetrics
+}
+
+// ImageStreamMetrics reports storage and traffic counters aggregated across every tag of an
+// ImageStream.
+type ImageStreamMetrics struct {
+	kapi.TypeMeta
+	kapi.ObjectMeta
+
+	// LayerBytesStored is the sum of LayerBytesStored of every Image currently tagged into the
+	// stream.
+	LayerBytesStored int64
+	// UniqueBlobBytes is the portion of LayerBytesStored not shared with any Image outside the
+	// stream.
+	UniqueBlobBytes int64
+	PullsPerMinute  float64
+	PushesPerMinute float64
+	LastPullTime    *unversioned.Time
+}
+
+// ImageStreamMetricsList is a list of ImageStreamMetrics.
+type ImageStreamMetricsList struct {
+	kapi.TypeMeta
+	kapi.ListMeta
+
+	Items []ImageStreamMetrics
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+// GroupName is the API group ImageMetrics and ImageStreamMetrics are served under.
+const GroupName = "metrics"
+
+// SchemeGroupVersion is the externally-served version of the metrics group that carries
+// ImageMetrics and ImageStreamMetrics.
+var SchemeGroupVersion = unversioned.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// Codec encodes and decodes ImageMetrics and ImageStreamMetrics as v1alpha1.
+var Codec = runtime.CodecFor(api.Scheme, SchemeGroupVersion.String())
+
+func init() {
+	api.Scheme.AddKnownTypes(SchemeGroupVersion,
+		&ImageMetrics{},
+		&ImageMetricsList{},
+		&ImageStreamMetrics{},
+		&ImageStreamMetricsList{},
+	)
+}
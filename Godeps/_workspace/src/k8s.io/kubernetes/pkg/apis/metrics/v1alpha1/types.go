@@ -0,0 +1,64 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 is the v1alpha1 version of the metrics API group.
+package v1alpha1
+
+import (
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// ImageMetrics reports storage and traffic counters for a single Image.
+type ImageMetrics struct {
+	unversioned.TypeMeta `json:",inline"`
+	v1.ObjectMeta        `json:"metadata,omitempty"`
+
+	LayerBytesStored int64             `json:"layerBytesStored"`
+	UniqueBlobBytes  int64             `json:"uniqueBlobBytes"`
+	PullsPerMinute   float64           `json:"pullsPerMinute"`
+	PushesPerMinute  float64           `json:"pushesPerMinute"`
+	LastPullTime     *unversioned.Time `json:"lastPullTime,omitempty"`
+}
+
+// ImageMetricsList is a list of ImageMetrics.
+type ImageMetricsList struct {
+	unversioned.TypeMeta `json:",inline"`
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	Items []ImageMetrics `json:"items"`
+}
+
+// ImageStreamMetrics reports storage and traffic counters aggregated across every tag of an
+// ImageStream.
+type ImageStreamMetrics struct {
+	unversioned.TypeMeta `json:",inline"`
+	v1.ObjectMeta        `json:"metadata,omitempty"`
+
+	LayerBytesStored int64             `json:"layerBytesStored"`
+	UniqueBlobBytes  int64             `json:"uniqueBlobBytes"`
+	PullsPerMinute   float64           `json:"pullsPerMinute"`
+	PushesPerMinute  float64           `json:"pushesPerMinute"`
+	LastPullTime     *unversioned.Time `json:"lastPullTime,omitempty"`
+}
+
+// ImageStreamMetricsList is a list of ImageStreamMetrics.
+type ImageStreamMetricsList struct {
+	unversioned.TypeMeta `json:",inline"`
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	Items []ImageStreamMetrics `json:"items"`
+}
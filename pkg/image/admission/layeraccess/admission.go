@@ -0,0 +1,72 @@
+package layeraccess
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/admission"
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// imageStreamsLayersResource is the resource a pushing user must `update` against an ImageStream
+// that already tags a digest before another Image with that same name/digest can be created.
+const imageStreamsLayersResource = "imagestreams/layers"
+
+// plugin denies `POST /images` from a user who cannot push to any ImageStream that currently
+// tags the pushed digest. Without this, any authenticated user who learns a tagged digest could
+// create its cluster-scoped Image record, since Images themselves carry no namespace to
+// authorize against. A digest that no ImageStream tags yet is admitted without a check.
+type plugin struct {
+	*admission.Handler
+
+	streams api.ImageStreamLister
+	sar     api.SubjectAccessChecker
+}
+
+// NewPlugin returns an admission.Interface that gates image creation behind a
+// SubjectAccessReview against every ImageStream tagging the pushed digest.
+func NewPlugin(streams api.ImageStreamLister, sar api.SubjectAccessChecker) admission.Interface {
+	return &plugin{
+		Handler: admission.NewHandler(admission.Create),
+		streams: streams,
+		sar:     sar,
+	}
+}
+
+func (p *plugin) Admit(a admission.Attributes) error {
+	if a.GetResource() != "images" || a.GetSubresource() != "" {
+		return nil
+	}
+	image, ok := a.GetObject().(*api.Image)
+	if !ok {
+		return nil
+	}
+
+	refs, err := p.streams.ImageStreamsForImage(image.Name)
+	if err != nil {
+		return admission.NewForbidden(a, err)
+	}
+
+	// no ImageStream references this digest yet: there is nothing to authorize against, so let
+	// the integrated registry's own push authorization (checked before the manifest is even
+	// accepted) stand as the only gate.
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ctx := kapi.WithUser(kapi.NewContext(), a.GetUserInfo())
+	for _, ref := range refs {
+		allowed, err := p.sar.Allowed(ctx, ref.Namespace, "update", imageStreamsLayersResource)
+		if err != nil {
+			util.HandleError(fmt.Errorf("Unable to check imagestreams/layers access for %q in %q: %v", image.Name, ref.Namespace, err))
+			continue
+		}
+		if allowed {
+			return nil
+		}
+	}
+
+	return admission.NewForbidden(a, fmt.Errorf("user %q cannot push to any imagestream tagging image %q", a.GetUserInfo().GetName(), image.Name))
+}
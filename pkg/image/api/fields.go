@@ -0,0 +1,34 @@
+package api
+
+import (
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// IndexedImageFields are the single-valued fields of an Image that the registry storage builds
+// a secondary etcd index on, so that `oc get images --field-selector=...` on one of them is a
+// point lookup rather than a full list-and-decode scan.
+var IndexedImageFields = []string{"metadata.name", "dockerImageReference"}
+
+// LayersDigestIndexField is the name of the multi-value index keyed on every layer digest an
+// Image references, used to answer "which images contain this layer digest" without a scan.
+// It is not part of IndexedImageFields because a field selector matches at most one value per
+// key, while an Image may reference many layers.
+const LayersDigestIndexField = "layers.digest"
+
+// ImageToSelectableFields returns a field set that can be used for filter selection.
+func ImageToSelectableFields(image *Image) fields.Set {
+	return fields.Set{
+		"metadata.name":        image.Name,
+		"dockerImageReference": image.DockerImageReference,
+	}
+}
+
+// ImageLayerDigests returns every layer digest referenced by image, in the order they appear in
+// DockerImageLayers. The registry storage indexes these under LayersDigestIndexField.
+func ImageLayerDigests(image *Image) []string {
+	digests := make([]string, 0, len(image.DockerImageLayers))
+	for _, layer := range image.DockerImageLayers {
+		digests = append(digests, layer.Name)
+	}
+	return digests
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DockerImageManifestSchema1MediaType is the media type of the legacy, signed Docker v2
+	// schema 1 manifest. It is assumed when DockerImageManifestMediaType is empty.
+	DockerImageManifestSchema1MediaType = "application/vnd.docker.distribution.manifest.v1+json"
+	// DockerImageManifestSchema2MediaType is the media type of a Docker v2 schema 2 manifest.
+	DockerImageManifestSchema2MediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	// DockerImageManifestSchema2ConfigMediaType is the media type of the config blob referenced
+	// by a schema 2 manifest.
+	DockerImageManifestSchema2ConfigMediaType = "application/vnd.docker.container.image.v1+json"
+	// DockerImageManifestListMediaType is the media type of a Docker manifest list, used to
+	// reference per-platform manifests from a single multi-arch tag.
+	DockerImageManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// ImageManifestMediaType is the media type of an OCI image manifest.
+	ImageManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	// ImageIndexMediaType is the media type of an OCI image index, the OCI analogue of a
+	// Docker manifest list.
+	ImageIndexMediaType = "application/vnd.oci.image.index.v1+json"
+
+	// DefaultImageTag is used when an image tag is needed and the user didn't specify one.
+	DefaultImageTag = "latest"
+)
+
+// ManifestDescriptor describes a blob referenced from a schema 2 or OCI manifest. For an entry
+// in a manifest list or OCI image index, Platform identifies which platform it is for; it is
+// exported so ExpandManifestList's caller can label the per-platform Images it creates.
+type ManifestDescriptor struct {
+	MediaType string         `json:"mediaType"`
+	Size      int64          `json:"size"`
+	Digest    string         `json:"digest"`
+	Platform  *ImagePlatform `json:"platform,omitempty"`
+}
+
+// ImagePlatform identifies the OS/architecture a manifest list entry applies to.
+type ImagePlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// dockerManifestSchema2 is the subset of the Docker v2 schema 2 (and OCI image) manifest
+// that imageWithMetadata needs in order to populate DockerImageLayers and fetch the config blob.
+type dockerManifestSchema2 struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType"`
+	Config        ManifestDescriptor   `json:"config"`
+	Layers        []ManifestDescriptor `json:"layers"`
+}
+
+// dockerManifestList is the subset of the Docker manifest list (and OCI image index) that
+// imageWithMetadata needs in order to recognize a multi-arch reference.
+type dockerManifestList struct {
+	SchemaVersion int                  `json:"schemaVersion"`
+	MediaType     string               `json:"mediaType"`
+	Manifests     []ManifestDescriptor `json:"manifests"`
+}
+
+// dockerManifestSchema1 is the subset of the legacy, signed Docker v2 schema 1 manifest that
+// imageWithMetadata needs. The image metadata is carried as a string-encoded DockerImage
+// inside History[0].V1Compatibility, the entry for the newest (top) layer; FSLayers and History
+// are both ordered from the top layer down to the base.
+type dockerManifestSchema1 struct {
+	Name         string          `json:"name"`
+	Tag          string          `json:"tag"`
+	Architecture string          `json:"architecture"`
+	FSLayers     []dockerFSLayer `json:"fsLayers"`
+	History      []dockerHistory `json:"history"`
+}
+
+type dockerFSLayer struct {
+	BlobSum string `json:"blobSum"`
+}
+
+type dockerHistory struct {
+	V1Compatibility string `json:"v1Compatibility"`
+}
+
+// ManifestMatchesImage returns true when the manifest digest embedded in the image name still
+// matches the content of dockerImageManifest. It is used to reject updates that would make the
+// stored manifest diverge from the content-addressable name the image was created with.
+func ManifestMatchesImage(image *Image, dockerImageManifest []byte) (bool, error) {
+	digest, err := ManifestDigest(image.DockerImageManifestMediaType, dockerImageManifest)
+	if err != nil {
+		return false, err
+	}
+	return digest == image.Name, nil
+}
+
+// ErrSchema1DigestUnsupported is returned by ManifestDigest for a schema 1 manifest: schema 1 is
+// a signed JWS document whose registry digest is computed over the original signed payload bytes
+// with the trailing "signatures" block stripped at its exact byte offset (libtrust's JSDigest).
+// Re-serializing the decoded JSON does not reproduce those bytes -- it re-sorts keys and drops
+// the original formatting -- so without vendoring libtrust's canonicalization this package cannot
+// verify a schema 1 manifest's digest and must not claim to.
+var ErrSchema1DigestUnsupported = fmt.Errorf("digest verification is not supported for schema 1 manifests")
+
+// ManifestDigest returns the content-addressable digest of the given raw manifest using the
+// same algorithm the registry uses to name it, so that newly fetched manifests can be compared
+// against existing Image names. It returns ErrSchema1DigestUnsupported for a schema 1 manifest.
+func ManifestDigest(mediaType string, raw []byte) (string, error) {
+	switch mediaType {
+	case DockerImageManifestSchema2MediaType, ImageManifestMediaType, DockerImageManifestListMediaType, ImageIndexMediaType:
+		// schema 2 and OCI manifests are digested over the exact bytes served by the registry.
+		return sha256Digest(raw), nil
+	default:
+		return "", ErrSchema1DigestUnsupported
+	}
+}
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("sha256:%x", sum)
+}
+
+// dockerForeignLayerMediaType marks a layer as non-distributable: the registry serves it by
+// reference (e.g. a Windows base layer hosted by Microsoft) rather than storing the content
+// itself, so it must not be counted against project image storage quotas.
+const dockerForeignLayerMediaType = "application/vnd.docker.image.rootfs.foreign.diff.tar.gzip"
+
+// IsForeignLayer returns true if mediaType identifies a non-distributable layer that the
+// registry serves by reference instead of storing, and which must therefore be excluded from
+// storage quota accounting.
+func IsForeignLayer(mediaType string) bool {
+	return mediaType == dockerForeignLayerMediaType
+}
+
+// IsManifestList returns true if the provided media type identifies a Docker manifest list or
+// an OCI image index, either of which references one manifest per platform.
+func IsManifestList(mediaType string) bool {
+	return mediaType == DockerImageManifestListMediaType || mediaType == ImageIndexMediaType
+}
+
+// IsSchema2 returns true if the provided media type identifies a Docker v2 schema 2 manifest or
+// an OCI image manifest, both of which carry a config blob digest and per-layer descriptors.
+func IsSchema2(mediaType string) bool {
+	return mediaType == DockerImageManifestSchema2MediaType || mediaType == ImageManifestMediaType
+}
+
+// imageWithMetadataFromSchema2 populates image.DockerImageLayers and image.DockerImageMetadata
+// from a parsed schema 2 (or OCI) manifest and its already-fetched config blob. Foreign layers
+// (those whose media type marks them as non-distributable) are preserved in DockerImageLayers so
+// callers can display them, but must be excluded from storage quota accounting by the caller.
+func imageWithMetadataFromSchema2(image *Image, manifest dockerManifestSchema2, config DockerImage) error {
+	layers := make([]ImageLayer, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		layers = append(layers, ImageLayer{
+			Name:      layer.Digest,
+			LayerSize: layer.Size,
+			MediaType: layer.MediaType,
+		})
+	}
+	image.DockerImageLayers = layers
+	image.DockerImageMetadata = config
+	image.DockerImageMetadataVersion = "1.0"
+	return nil
+}
+
+// imageWithMetadataFromSchema1 populates image.DockerImageLayers and image.DockerImageMetadata
+// from a parsed legacy schema 1 manifest. The schema 1 format carries its config as the
+// v1Compatibility blob of the newest (first) history entry rather than as a separate blob.
+func imageWithMetadataFromSchema1(image *Image, manifest dockerManifestSchema1) error {
+	layers := make([]ImageLayer, 0, len(manifest.FSLayers))
+	for i := len(manifest.FSLayers) - 1; i >= 0; i-- {
+		layers = append(layers, ImageLayer{Name: manifest.FSLayers[i].BlobSum})
+	}
+	image.DockerImageLayers = layers
+	image.DockerImageMetadataVersion = "1.0"
+	if len(manifest.History) == 0 {
+		return nil
+	}
+	return json.Unmarshal([]byte(manifest.History[0].V1Compatibility), &image.DockerImageMetadata)
+}
+
+// ConfigBlobFetcher retrieves the config blob referenced by a schema 2 or OCI manifest by
+// digest. It is satisfied by the registry client used in production and by a fake in tests.
+type ConfigBlobFetcher interface {
+	Get(dockerImageReference, digest string) ([]byte, error)
+}
+
+// ImageWithMetadata mutates the passed Image, parsing DockerImageManifest (using
+// DockerImageManifestMediaType to select the schema) and filling in DockerImageMetadata and
+// DockerImageLayers. The fields are recomputed from the manifest on every call. It fetches no
+// config blob, so for a schema 2 or OCI manifest it sets DockerImageMetadata to an empty value,
+// replacing any metadata an earlier ImageWithMetadataAndBlobs call filled in.
+func ImageWithMetadata(image *Image) error {
+	return imageWithMetadata(image, nil)
+}
+
+// ImageWithMetadataAndBlobs behaves like ImageWithMetadata, but additionally fetches the config
+// blob referenced by a schema 2 or OCI manifest through fetcher so that DockerImageMetadata can
+// be populated from it instead of from an embedded v1Compatibility blob.
+func ImageWithMetadataAndBlobs(image *Image, fetcher ConfigBlobFetcher) error {
+	return imageWithMetadata(image, fetcher)
+}
+
+func imageWithMetadata(image *Image, fetcher ConfigBlobFetcher) error {
+	if len(image.DockerImageManifest) == 0 {
+		return nil
+	}
+	raw := []byte(image.DockerImageManifest)
+
+	if IsManifestList(image.DockerImageManifestMediaType) {
+		// Manifest lists have no per-layer or config metadata of their own; each referenced
+		// platform manifest is expanded into its own Image by the caller (registry storage).
+		return nil
+	}
+
+	if !IsSchema2(image.DockerImageManifestMediaType) {
+		var manifest dockerManifestSchema1
+		if err := json.Unmarshal(raw, &manifest); err != nil {
+			return fmt.Errorf("error unmarshaling docker schema1 manifest: %v", err)
+		}
+		return imageWithMetadataFromSchema1(image, manifest)
+	}
+
+	var manifest dockerManifestSchema2
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return fmt.Errorf("error unmarshaling docker schema2 manifest: %v", err)
+	}
+
+	var config DockerImage
+	if fetcher != nil {
+		blob, err := fetcher.Get(image.DockerImageReference, manifest.Config.Digest)
+		if err != nil {
+			return fmt.Errorf("error fetching image config %s: %v", manifest.Config.Digest, err)
+		}
+		if err := json.Unmarshal(blob, &config); err != nil {
+			return fmt.Errorf("error unmarshaling image config %s: %v", manifest.Config.Digest, err)
+		}
+	}
+	return imageWithMetadataFromSchema2(image, manifest, config)
+}
+
+// ManifestListPlatforms returns the digest and platform of each manifest referenced by a Docker
+// manifest list or OCI image index, so the caller can expand it into one Image per platform,
+// each linked back to list's digest as its parent.
+func ManifestListPlatforms(raw []byte) ([]ManifestDescriptor, error) {
+	var list dockerManifestList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("error unmarshaling manifest list: %v", err)
+	}
+	return list.Manifests, nil
+}
+
+// ExpandManifestList parses a Docker manifest list or OCI image index (raw, named by listDigest
+// and pulled through listDockerImageReference) and returns one Image stub per referenced
+// platform, each named after its own manifest digest, with ManifestListDigest set to listDigest
+// so it can be traced back to the list it came from, and with DockerImageReference rewritten to
+// pull that platform's own digest from the same repository. The platform manifests themselves
+// are not fetched: DockerImageManifest and DockerImageLayers are left empty, and of
+// DockerImageMetadata only Architecture is set, from the entry's platform when it has one.
+func ExpandManifestList(listDigest, listDockerImageReference string, raw []byte) ([]*Image, error) {
+	manifests, err := ManifestListPlatforms(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	repository := repositoryReference(listDockerImageReference)
+	images := make([]*Image, 0, len(manifests))
+	for _, m := range manifests {
+		image := &Image{
+			ManifestListDigest:           listDigest,
+			DockerImageManifestMediaType: m.MediaType,
+			DockerImageReference:         repository + "@" + m.Digest,
+		}
+		image.Name = m.Digest
+		if m.Platform != nil {
+			image.DockerImageMetadata.Architecture = m.Platform.Architecture
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+// repositoryReference strips the trailing "@digest" or ":tag" from a DockerImageReference,
+// leaving the bare "registry/namespace/name" a different digest can be appended to. A colon is
+// only treated as a tag separator when it appears after the last slash, so a registry's own
+// "host:port" prefix isn't mistaken for one.
+func repositoryReference(ref string) string {
+	if i := strings.LastIndex(ref, "@"); i != -1 {
+		return ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i]
+	}
+	return ref
+}
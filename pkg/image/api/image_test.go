@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+func TestManifestDigestSchema2(t *testing.T) {
+	raw := []byte(`{"schemaVersion":2,"mediaType":"application/vnd.docker.distribution.manifest.v2+json","config":{"digest":"sha256:abc"}}`)
+	sum := sha256.Sum256(raw)
+	want := fmt.Sprintf("sha256:%x", sum)
+
+	got, err := ManifestDigest(DockerImageManifestSchema2MediaType, raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("got digest %q, want %q", got, want)
+	}
+}
+
+func TestExpandManifestList(t *testing.T) {
+	raw := []byte(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+		"manifests": [
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 1, "digest": "sha256:amd64", "platform": {"architecture": "amd64", "os": "linux"}},
+			{"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "size": 1, "digest": "sha256:arm64", "platform": {"architecture": "arm64", "os": "linux"}}
+		]
+	}`)
+
+	const parentReference = "registry.example.com:5000/foo/bar@sha256:list"
+	images, err := ExpandManifestList("sha256:list", parentReference, raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("got %d images, want 2", len(images))
+	}
+	for i, want := range []struct {
+		digest       string
+		architecture string
+	}{
+		{"sha256:amd64", "amd64"},
+		{"sha256:arm64", "arm64"},
+	} {
+		if images[i].Name != want.digest {
+			t.Errorf("image %d: got name %q, want %q", i, images[i].Name, want.digest)
+		}
+		if images[i].ManifestListDigest != "sha256:list" {
+			t.Errorf("image %d: got ManifestListDigest %q, want %q", i, images[i].ManifestListDigest, "sha256:list")
+		}
+		wantReference := "registry.example.com:5000/foo/bar@" + want.digest
+		if images[i].DockerImageReference != wantReference {
+			t.Errorf("image %d: got DockerImageReference %q, want %q", i, images[i].DockerImageReference, wantReference)
+		}
+		if images[i].DockerImageMetadata.Architecture != want.architecture {
+			t.Errorf("image %d: got architecture %q, want %q", i, images[i].DockerImageMetadata.Architecture, want.architecture)
+		}
+	}
+}
+
+func TestRepositoryReference(t *testing.T) {
+	cases := []struct {
+		ref  string
+		want string
+	}{
+		{"registry.example.com/foo/bar@sha256:abc", "registry.example.com/foo/bar"},
+		{"registry.example.com/foo/bar:latest", "registry.example.com/foo/bar"},
+		{"registry.example.com:5000/foo/bar:latest", "registry.example.com:5000/foo/bar"},
+		{"registry.example.com:5000/foo/bar", "registry.example.com:5000/foo/bar"},
+	}
+	for _, c := range cases {
+		if got := repositoryReference(c.ref); got != c.want {
+			t.Errorf("repositoryReference(%q) = %q, want %q", c.ref, got, c.want)
+		}
+	}
+}
+
+func TestManifestDigestSchema1Unsupported(t *testing.T) {
+	raw := []byte(`{"name":"foo","tag":"latest","signatures":[{"header":{}}]}`)
+
+	_, err := ManifestDigest(DockerImageManifestSchema1MediaType, raw)
+	if err != ErrSchema1DigestUnsupported {
+		t.Fatalf("got err %v, want ErrSchema1DigestUnsupported", err)
+	}
+
+	// empty media type is also treated as schema 1
+	_, err = ManifestDigest("", raw)
+	if err != ErrSchema1DigestUnsupported {
+		t.Fatalf("got err %v, want ErrSchema1DigestUnsupported for empty media type", err)
+	}
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// ImageStreamReference identifies an ImageStream that currently tags some Image.
+type ImageStreamReference struct {
+	Namespace string
+	Name      string
+}
+
+// ImageStreamLister finds every ImageStream that currently tags the Image named by digest.
+// Images are cluster-scoped, so the Image REST strategy consults it to find which namespaces a
+// SubjectAccessReview should be evaluated against before a caller is allowed to read or push an
+// image's manifest/layers.
+type ImageStreamLister interface {
+	ImageStreamsForImage(imageName string) ([]ImageStreamReference, error)
+}
+
+// SubjectAccessChecker reports whether the user carried on ctx may perform verb on resource in
+// namespace, mirroring a LocalSubjectAccessReview against that namespace.
+type SubjectAccessChecker interface {
+	Allowed(ctx kapi.Context, namespace, verb, resource string) (bool, error)
+}
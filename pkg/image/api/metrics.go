@@ -0,0 +1,28 @@
+package api
+
+import (
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// Counters reports the traffic and storage counters backing ImageMetrics and ImageStreamMetrics.
+// It is implemented by the registry middleware (which observes pushes and pulls as they happen)
+// and consulted by the metrics REST storage on every Get; the pruner updates the same counters
+// when it reclaims blob storage during GC.
+type Counters interface {
+	// ImageCounters returns the counters recorded for the Image named by digest, or ok=false if
+	// no traffic has been observed for it yet.
+	ImageCounters(digest string) (counters ImageTrafficCounters, ok bool)
+	// ImageStreamCounters returns the counters aggregated across every tag of the named
+	// ImageStream, or ok=false if the stream has no tags with recorded traffic.
+	ImageStreamCounters(namespace, name string) (counters ImageTrafficCounters, ok bool)
+}
+
+// ImageTrafficCounters is the set of counters a Counters implementation reports for either an
+// Image or an ImageStream.
+type ImageTrafficCounters struct {
+	LayerBytesStored int64
+	UniqueBlobBytes  int64
+	PullsPerMinute   float64
+	PushesPerMinute  float64
+	LastPullTime     *unversioned.Time
+}
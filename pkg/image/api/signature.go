@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// simpleSigningPayload is the "critical"/"optional" JSON document described by the Red Hat
+// Atomic/simple signing specification. It is the payload carried by an ImageSignature, either
+// directly (ImageSignatureTypeAtomicImageV1) or wrapped in a detached PGP/PKCS7 envelope.
+type simpleSigningPayload struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+	} `json:"critical"`
+	Optional map[string]string `json:"optional"`
+}
+
+// TrustStore resolves the signature envelope carried by ImageSignature.Content for a signed
+// type (PGP or PKCS7), verifying it against the configured trusted keys/CAs and returning the
+// embedded simple-signing payload plus a description of the signer. The unsigned
+// ImageSignatureTypeAtomicImageV1 type does not go through a TrustStore.
+type TrustStore interface {
+	Verify(signatureType string, content []byte) (payload []byte, signer string, err error)
+}
+
+// ExtractSignaturePayload returns the simple-signing JSON payload carried by signature,
+// verifying the envelope against trustStore first when the signature's type requires it.
+func ExtractSignaturePayload(signature *ImageSignature, trustStore TrustStore) (payload []byte, signer string, err error) {
+	switch signature.Type {
+	case ImageSignatureTypeAtomicImageV1:
+		return signature.Content, "", nil
+	case ImageSignatureTypePGP, ImageSignatureTypePKCS7:
+		if trustStore == nil {
+			return nil, "", fmt.Errorf("no trust store configured to verify signature type %q", signature.Type)
+		}
+		return trustStore.Verify(signature.Type, signature.Content)
+	default:
+		return nil, "", fmt.Errorf("unknown signature type %q", signature.Type)
+	}
+}
+
+// VerifySignature checks that signature's embedded claims match the image it is attached to,
+// returning the verified docker reference and signed claims on success. The caller is
+// responsible for turning a non-nil error into a SignatureCondition rather than failing the
+// request outright, so that an untrusted or unverifiable signature can still be recorded.
+func VerifySignature(image *Image, signature *ImageSignature, trustStore TrustStore) (dockerReference string, claims map[string]string, err error) {
+	raw, _, err := ExtractSignaturePayload(signature, trustStore)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var payload simpleSigningPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return "", nil, fmt.Errorf("error unmarshaling signature payload: %v", err)
+	}
+
+	if payload.Critical.Image.DockerManifestDigest != image.Name {
+		return "", nil, fmt.Errorf("signature critical.image.docker-manifest-digest %q does not match image %q", payload.Critical.Image.DockerManifestDigest, image.Name)
+	}
+	if payload.Critical.Identity.DockerReference != image.DockerImageReference {
+		return "", nil, fmt.Errorf("signature critical.identity.docker-reference %q does not match image %q", payload.Critical.Identity.DockerReference, image.DockerImageReference)
+	}
+
+	return payload.Critical.Identity.DockerReference, payload.Optional, nil
+}
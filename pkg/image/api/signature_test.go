@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func atomicSignatureContent(t *testing.T, digest, dockerReference string) []byte {
+	t.Helper()
+	content, err := json.Marshal(simpleSigningPayload{
+		Critical: struct {
+			Type  string `json:"type"`
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+			Identity struct {
+				DockerReference string `json:"docker-reference"`
+			} `json:"identity"`
+		}{
+			Image: struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			}{DockerManifestDigest: digest},
+			Identity: struct {
+				DockerReference string `json:"docker-reference"`
+			}{DockerReference: dockerReference},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to build signature content: %v", err)
+	}
+	return content
+}
+
+func TestVerifySignatureRejectsMismatchedDockerReference(t *testing.T) {
+	image := &Image{
+		DockerImageReference: "registry.example.com/foo/bar@sha256:abc",
+	}
+	image.Name = "sha256:abc"
+	signature := &ImageSignature{
+		Type:    ImageSignatureTypeAtomicImageV1,
+		Content: atomicSignatureContent(t, image.Name, "registry.example.com/other/ref@sha256:abc"),
+	}
+
+	if _, _, err := VerifySignature(image, signature, nil); err == nil {
+		t.Fatal("expected an error for a signature whose docker-reference does not match the image")
+	}
+}
+
+func TestVerifySignatureAcceptsMatchingDockerReference(t *testing.T) {
+	image := &Image{
+		DockerImageReference: "registry.example.com/foo/bar@sha256:abc",
+	}
+	image.Name = "sha256:abc"
+	signature := &ImageSignature{
+		Type:    ImageSignatureTypeAtomicImageV1,
+		Content: atomicSignatureContent(t, image.Name, image.DockerImageReference),
+	}
+
+	dockerReference, _, err := VerifySignature(image, signature, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dockerReference != image.DockerImageReference {
+		t.Errorf("got docker reference %q, want %q", dockerReference, image.DockerImageReference)
+	}
+}
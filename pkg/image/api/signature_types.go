@@ -0,0 +1,92 @@
+package api
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// Known values for ImageSignature.Type, identifying the signature envelope format.
+const (
+	// ImageSignatureTypeAtomicImageV1 is an unwrapped Red Hat Atomic/simple signing JSON
+	// payload, trusted on the basis of who was authorized to push it rather than a detached
+	// cryptographic signature.
+	ImageSignatureTypeAtomicImageV1 = "AtomicImageV1"
+	// ImageSignatureTypePGP is a simple signing payload wrapped in a detached PGP signature.
+	ImageSignatureTypePGP = "PGP"
+	// ImageSignatureTypePKCS7 is a simple signing payload wrapped in a detached X.509/PKCS7
+	// signature.
+	ImageSignatureTypePKCS7 = "PKCS7"
+)
+
+// ImageSignature holds a signature of an Image. It is stored as a subresource of the Image it
+// attests to and named after a digest of its own content.
+type ImageSignature struct {
+	kapi.TypeMeta
+	kapi.ObjectMeta
+
+	// Type identifies the signature envelope: ImageSignatureTypeAtomicImageV1 for a bare simple
+	// signing JSON document, or one of the signed types for a payload wrapped in a detached
+	// signature.
+	Type string
+	// Content is the raw signature as submitted by the client.
+	Content []byte
+
+	// Conditions represent the latest available observations of the signature's trust state.
+	Conditions []SignatureCondition
+
+	// ImageIdentity holds the docker-reference claim verified from the signature payload, filled
+	// in by the image strategy on create when the signature verifies (Trusted status True).
+	ImageIdentity string
+	// SignedClaims holds the verified "optional" claims from the signature payload.
+	SignedClaims map[string]string
+
+	Created  *unversioned.Time
+	IssuedBy *SignatureIssuer
+	IssuedTo *SignatureSubject
+}
+
+// SignatureConditionType is a camel-cased condition name for an ImageSignature.
+type SignatureConditionType string
+
+const (
+	// SignatureTrusted means the signature's critical claims match the Image it is attached to
+	// and, for a signed type (PGP or PKCS7), its envelope was verified against a configured trust
+	// anchor (key or CA). An unwrapped AtomicImageV1 payload is not checked against any anchor.
+	SignatureTrusted SignatureConditionType = "Trusted"
+	// SignatureRevoked means a previously trusted signature's signer has since been revoked
+	// (key rotated out of the trusted set, or certificate revoked).
+	SignatureRevoked SignatureConditionType = "Revoked"
+)
+
+// SignatureCondition describes an image signature condition of particular kind at a particular
+// probe time, mirroring the pattern used for node/pod conditions elsewhere in the API.
+type SignatureCondition struct {
+	Type   SignatureConditionType
+	Status kapi.ConditionStatus
+
+	LastProbeTime      unversioned.Time
+	LastTransitionTime unversioned.Time
+
+	Reason  string
+	Message string
+}
+
+// SignatureSubject holds information about the entity a signature was issued to.
+type SignatureSubject struct {
+	PublicKeyID string
+	CommonName  string
+}
+
+// SignatureIssuer holds information about the entity that issued a signature.
+type SignatureIssuer struct {
+	CommonName   string
+	Organization string
+}
+
+// ImageSignatureList is a list of ImageSignature objects.
+type ImageSignatureList struct {
+	kapi.TypeMeta
+	kapi.ListMeta
+
+	Items []ImageSignature
+}
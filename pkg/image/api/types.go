@@ -0,0 +1,84 @@
+package api
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// Image is an immutable representation of a Docker image and metadata at a point in time.
+type Image struct {
+	kapi.TypeMeta
+	kapi.ObjectMeta
+
+	// DockerImageReference is the string that can be used to pull this image.
+	DockerImageReference string
+	// DockerImageMetadata contains metadata about this image, extracted from the config blob of a
+	// schema 2 or OCI manifest, or from the newest v1Compatibility entry of a schema 1 manifest.
+	DockerImageMetadata DockerImage
+	// DockerImageMetadataVersion conveys the version of the object, which if empty defaults to "1.0".
+	DockerImageMetadataVersion string
+	// DockerImageManifest is the raw JSON of the manifest as retrieved from the registry.
+	DockerImageManifest string
+	// DockerImageManifestMediaType specifies the mediaType of DockerImageManifest: one of the
+	// schema1, schema2, or OCI image manifest media types, or the Docker manifest list or OCI
+	// image index media type. Empty implies schema1.
+	DockerImageManifestMediaType string
+	// DockerImageLayers represents the layers in the image, ordered from base image to top layer.
+	DockerImageLayers []ImageLayer
+
+	// Signatures holds all signatures of the image.
+	Signatures []ImageSignature
+
+	// ManifestListDigest is the digest of the manifest list or OCI image index this Image was
+	// expanded from, for a per-platform Image created by ExpandManifestList. Empty for an Image
+	// that was pushed directly rather than reached through a manifest list.
+	ManifestListDigest string
+}
+
+// ImageLayer represents a single layer of the image. Some images may have multiple layers.
+// Some may have none.
+type ImageLayer struct {
+	// Name of the layer as defined by the underlying store.
+	Name string
+	// LayerSize of the layer as defined by the underlying store.
+	LayerSize int64
+	// MediaType of the referenced object.
+	MediaType string
+}
+
+// DockerImage is the type representing a docker image and its various properties when
+// retrieved from the Docker client API.
+type DockerImage struct {
+	ID              string
+	Parent          string
+	Comment         string
+	Created         unversioned.Time
+	Container       string
+	ContainerConfig DockerConfig
+	DockerVersion   string
+	Author          string
+	Config          *DockerConfig
+	Architecture    string
+	Size            int64
+}
+
+// DockerConfig is the list of configuration options used to create a container.
+type DockerConfig struct {
+	Hostname     string
+	User         string
+	Env          []string
+	Cmd          []string
+	Image        string
+	WorkingDir   string
+	Entrypoint   []string
+	Labels       map[string]string
+	ExposedPorts map[string]struct{}
+}
+
+// ImageList is a list of Image objects.
+type ImageList struct {
+	kapi.TypeMeta
+	kapi.ListMeta
+
+	Items []Image
+}
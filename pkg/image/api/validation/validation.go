@@ -0,0 +1,113 @@
+package validation
+
+import (
+	"encoding/json"
+
+	"k8s.io/kubernetes/pkg/api/validation"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// ValidateImage tests required fields for an Image.
+func ValidateImage(image *api.Image) field.ErrorList {
+	result := validation.ValidateObjectMeta(&image.ObjectMeta, false, validation.NameIsDNSSubdomain, field.NewPath("metadata"))
+
+	if len(image.DockerImageReference) == 0 {
+		result = append(result, field.Required(field.NewPath("dockerImageReference"), ""))
+	}
+
+	if len(image.DockerImageManifest) > 0 {
+		// Schema 1 manifests are signed JWS documents whose registry digest cannot be
+		// recomputed from the decoded JSON (see api.ErrSchema1DigestUnsupported); name/digest
+		// agreement for those is enforced by the registry that pushed them, not here.
+		if digest, err := api.ManifestDigest(image.DockerImageManifestMediaType, []byte(image.DockerImageManifest)); err == nil {
+			if digest != image.Name {
+				result = append(result, field.Invalid(field.NewPath("metadata", "name"), image.Name, "name must be the digest of dockerImageManifest"))
+			}
+		} else if err != api.ErrSchema1DigestUnsupported {
+			result = append(result, field.Invalid(field.NewPath("dockerImageManifest"), "<omitted>", err.Error()))
+		}
+	}
+
+	signaturesPath := field.NewPath("signatures")
+	for i := range image.Signatures {
+		result = append(result, validateImageSignature(image, &image.Signatures[i], signaturesPath.Index(i))...)
+	}
+
+	return result
+}
+
+// validateImageSignature checks the fields required on any ImageSignature attached to image,
+// and, for the unsigned AtomicImageV1 type whose claims can be read without a trust store,
+// rejects a signature whose critical claims do not describe the image it is attached to.
+func validateImageSignature(image *api.Image, signature *api.ImageSignature, fldPath *field.Path) field.ErrorList {
+	result := field.ErrorList{}
+
+	if len(signature.Type) == 0 {
+		result = append(result, field.Required(fldPath.Child("type"), ""))
+	}
+	if len(signature.Content) == 0 {
+		result = append(result, field.Required(fldPath.Child("content"), ""))
+	}
+
+	if signature.Type != api.ImageSignatureTypeAtomicImageV1 || len(signature.Content) == 0 {
+		return result
+	}
+
+	var payload struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+			Identity struct {
+				DockerReference string `json:"docker-reference"`
+			} `json:"identity"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(signature.Content, &payload); err != nil {
+		result = append(result, field.Invalid(fldPath.Child("content"), "<omitted>", err.Error()))
+		return result
+	}
+
+	if payload.Critical.Image.DockerManifestDigest != image.Name {
+		result = append(result, field.Invalid(fldPath.Child("content"), "<omitted>", "critical.image.docker-manifest-digest does not match the image name"))
+	}
+	if payload.Critical.Identity.DockerReference != image.DockerImageReference {
+		result = append(result, field.Invalid(fldPath.Child("content"), "<omitted>", "critical.identity.docker-reference does not match the image's dockerImageReference"))
+	}
+
+	return result
+}
+
+// ValidateImageSignature tests required fields for a standalone ImageSignature posted to the
+// imagesignatures subresource, before it has been attached to its Image and checked against that
+// Image's claims.
+func ValidateImageSignature(signature *api.ImageSignature) field.ErrorList {
+	result := validation.ValidateObjectMeta(&signature.ObjectMeta, false, validation.NameIsDNSSubdomain, field.NewPath("metadata"))
+
+	if len(signature.Type) == 0 {
+		result = append(result, field.Required(field.NewPath("type"), ""))
+	}
+	if len(signature.Content) == 0 {
+		result = append(result, field.Required(field.NewPath("content"), ""))
+	}
+
+	return result
+}
+
+// ValidateImageUpdate tests required fields for an Image update and rejects any attempt to
+// change fields that must stay in lockstep with the object's content-addressable name.
+func ValidateImageUpdate(old, image *api.Image) field.ErrorList {
+	result := validation.ValidateObjectMetaUpdate(&image.ObjectMeta, &old.ObjectMeta, field.NewPath("metadata"))
+	result = append(result, ValidateImage(image)...)
+
+	if image.DockerImageReference != old.DockerImageReference {
+		result = append(result, field.Invalid(field.NewPath("dockerImageReference"), image.DockerImageReference, "dockerImageReference is not a mutable field"))
+	}
+	if image.DockerImageManifest != old.DockerImageManifest {
+		result = append(result, field.Invalid(field.NewPath("dockerImageManifest"), "<omitted>", "dockerImageManifest is not a mutable field"))
+	}
+
+	return result
+}
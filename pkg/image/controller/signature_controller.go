@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// ImageSignatureClient is the subset of the image client the SignatureController needs: listing
+// images and persisting the signature conditions it recomputes.
+type ImageSignatureClient interface {
+	ListImages() ([]api.Image, error)
+	UpdateImage(image *api.Image) error
+}
+
+// SignatureController periodically re-verifies every signature attached to every Image against
+// the configured TrustStore, so that key rotation and revocation are reflected in an Image's
+// Signatures[].Conditions without requiring the image to be pushed again.
+type SignatureController struct {
+	client       ImageSignatureClient
+	trustStore   api.TrustStore
+	resyncPeriod time.Duration
+}
+
+// NewSignatureController creates a controller that re-verifies signatures every resyncPeriod.
+func NewSignatureController(client ImageSignatureClient, trustStore api.TrustStore, resyncPeriod time.Duration) *SignatureController {
+	return &SignatureController{
+		client:       client,
+		trustStore:   trustStore,
+		resyncPeriod: resyncPeriod,
+	}
+}
+
+// Run starts the controller's resync loop and blocks until stopCh is closed.
+func (c *SignatureController) Run(stopCh <-chan struct{}) {
+	glog.V(4).Infof("Starting image signature controller")
+	util.Until(c.resync, c.resyncPeriod, stopCh)
+}
+
+// resync re-verifies the signatures on every image once, logging but not failing on a per-image
+// error so that one bad image (e.g. a transient etcd conflict) doesn't block the rest.
+func (c *SignatureController) resync() {
+	images, err := c.client.ListImages()
+	if err != nil {
+		glog.V(2).Infof("Unable to list images for signature re-verification: %v", err)
+		return
+	}
+
+	for i := range images {
+		image := &images[i]
+		if len(image.Signatures) == 0 {
+			continue
+		}
+		if c.reverify(image) {
+			if err := c.client.UpdateImage(image); err != nil {
+				glog.V(2).Infof("Unable to update signature conditions for image %q: %v", image.Name, err)
+			}
+		}
+	}
+}
+
+// reverify recomputes the Trusted condition of every signature on image and reports whether the
+// image needs to be persisted. It returns false when every signature's recomputed status matches
+// its existing Trusted condition, so that a routine resync across every signed Image doesn't
+// etcd-write each one just to bump a probe timestamp; resync already runs on resyncPeriod, so the
+// time a status was last confirmed is recoverable from the controller's own cadence without
+// storing it. Only an actual status change (including a signature's first verification) counts as
+// a change worth persisting.
+func (c *SignatureController) reverify(image *api.Image) bool {
+	changed := false
+	now := unversioned.Now()
+
+	for i := range image.Signatures {
+		signature := &image.Signatures[i]
+		_, _, err := api.VerifySignature(image, signature, c.trustStore)
+
+		status := kapi.ConditionTrue
+		reason, message := "", ""
+		if err != nil {
+			status = kapi.ConditionFalse
+			reason = "VerificationFailed"
+			message = err.Error()
+		}
+
+		if existing := trustedCondition(signature); existing != nil && existing.Status == status {
+			continue
+		}
+
+		changed = true
+		setTrustedCondition(signature, api.SignatureCondition{
+			Type:               api.SignatureTrusted,
+			Status:             status,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	return changed
+}
+
+// setTrustedCondition replaces the Trusted condition on signature with condition, leaving any
+// other condition (e.g. Revoked) in place, so that re-verifying trust never erases an unrelated
+// condition set by another controller.
+func setTrustedCondition(signature *api.ImageSignature, condition api.SignatureCondition) {
+	for i := range signature.Conditions {
+		if signature.Conditions[i].Type == condition.Type {
+			signature.Conditions[i] = condition
+			return
+		}
+	}
+	signature.Conditions = append(signature.Conditions, condition)
+}
+
+func trustedCondition(signature *api.ImageSignature) *api.SignatureCondition {
+	for i := range signature.Conditions {
+		if signature.Conditions[i].Type == api.SignatureTrusted {
+			return &signature.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+func TestReverifyPreservesRevokedConditionAndSkipsUnchangedStatus(t *testing.T) {
+	c := &SignatureController{}
+
+	image := &api.Image{
+		DockerImageReference: "registry.example.com/foo/bar@sha256:abc",
+		Signatures: []api.ImageSignature{
+			{
+				Type:    api.ImageSignatureTypeAtomicImageV1,
+				Content: atomicSignatureContent(t, "sha256:abc", "registry.example.com/foo/bar@sha256:abc"),
+				Conditions: []api.SignatureCondition{
+					{Type: api.SignatureRevoked, Status: kapi.ConditionTrue},
+					{Type: api.SignatureTrusted, Status: kapi.ConditionTrue},
+				},
+			},
+		},
+	}
+	image.Name = "sha256:abc"
+	wantProbeTime := image.Signatures[0].Conditions[1].LastProbeTime
+
+	if changed := c.reverify(image); changed {
+		t.Fatal("expected reverify to report no change when the recomputed status matches the existing Trusted condition")
+	}
+
+	conditions := image.Signatures[0].Conditions
+	if len(conditions) != 2 {
+		t.Fatalf("expected the Revoked condition to be preserved, got %+v", conditions)
+	}
+	var sawRevoked bool
+	for _, cond := range conditions {
+		if cond.Type == api.SignatureRevoked {
+			sawRevoked = true
+			if cond.Status != kapi.ConditionTrue {
+				t.Errorf("Revoked condition status changed unexpectedly: %+v", cond)
+			}
+		}
+		if cond.Type == api.SignatureTrusted && cond.LastProbeTime != wantProbeTime {
+			t.Errorf("LastProbeTime was rewritten despite no status change: got %v, want %v", cond.LastProbeTime, wantProbeTime)
+		}
+	}
+	if !sawRevoked {
+		t.Error("Revoked condition was dropped by reverify")
+	}
+}
+
+func TestReverifyPersistsActualStatusTransition(t *testing.T) {
+	c := &SignatureController{}
+
+	image := &api.Image{
+		DockerImageReference: "registry.example.com/foo/bar@sha256:abc",
+		Signatures: []api.ImageSignature{
+			{
+				Type:    api.ImageSignatureTypeAtomicImageV1,
+				Content: atomicSignatureContent(t, "sha256:abc", "registry.example.com/foo/bar@sha256:abc"),
+				Conditions: []api.SignatureCondition{
+					{Type: api.SignatureTrusted, Status: kapi.ConditionFalse},
+				},
+			},
+		},
+	}
+	image.Name = "sha256:abc"
+
+	if changed := c.reverify(image); !changed {
+		t.Fatal("expected reverify to report a change when the recomputed status differs from the existing Trusted condition")
+	}
+
+	condition := trustedCondition(&image.Signatures[0])
+	if condition == nil || condition.Status != kapi.ConditionTrue {
+		t.Fatalf("expected Trusted condition to transition to true, got %+v", condition)
+	}
+}
+
+func atomicSignatureContent(t *testing.T, digest, dockerReference string) []byte {
+	t.Helper()
+	return []byte(`{"critical":{"type":"atomic container signature","image":{"docker-manifest-digest":"` + digest + `"},"identity":{"docker-reference":"` + dockerReference + `"}}}`)
+}
@@ -0,0 +1,137 @@
+package etcd
+
+import (
+	"fmt"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	kapierrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/fields"
+	"k8s.io/kubernetes/pkg/labels"
+	"k8s.io/kubernetes/pkg/registry/generic"
+	etcdgeneric "k8s.io/kubernetes/pkg/registry/generic/etcd"
+	"k8s.io/kubernetes/pkg/runtime"
+	kstorage "k8s.io/kubernetes/pkg/storage"
+	"k8s.io/kubernetes/pkg/util"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/registry/image"
+)
+
+// imagePrefix is the etcd key root every Image is stored under.
+const imagePrefix = "/images"
+
+// REST implements the RESTStorage interface for Image, backed by etcd. It wraps the generic
+// etcd.Etcd store so it can apply image.LayerAccessStrategy's SAR gating on every read: the
+// generic store by itself has no hook for it, which is what let the layer-access gate added to
+// imageStrategy go unenforced.
+type REST struct {
+	*etcdgeneric.Etcd
+
+	access image.LayerAccessStrategy
+}
+
+// NewREST returns a RESTStorage for Images backed by s, gating reads behind a
+// SubjectAccessReview as configured by blobs, trustStore, streams, and sar. This is the one
+// place an imageStrategy is built for production use; callers must go through it (or
+// image.NewStrategy directly, for tests) rather than using the zero-value image.Strategy, which
+// has no config blob fetcher, trust store, or authorizer configured.
+func NewREST(s kstorage.Interface, blobs api.ConfigBlobFetcher, trustStore api.TrustStore, streams api.ImageStreamLister, sar api.SubjectAccessChecker) *REST {
+	strategy := image.NewStrategy(blobs, trustStore, streams, sar)
+
+	store := &etcdgeneric.Etcd{
+		NewFunc:     func() runtime.Object { return &api.Image{} },
+		NewListFunc: func() runtime.Object { return &api.ImageList{} },
+		KeyRootFunc: func(ctx kapi.Context) string {
+			return imagePrefix
+		},
+		KeyFunc: func(ctx kapi.Context, name string) (string, error) {
+			return imagePrefix + "/" + name, nil
+		},
+		ObjectNameFunc: func(obj runtime.Object) (string, error) {
+			return obj.(*api.Image).Name, nil
+		},
+		PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
+			return image.MatchImage(label, field)
+		},
+		Indexers: &kstorage.Indexers{
+			api.LayersDigestIndexField: func(obj interface{}) ([]string, error) {
+				return image.ImageLayerDigestIndexFunc(obj.(runtime.Object))
+			},
+		},
+		EndpointName: "images",
+
+		CreateStrategy: strategy,
+		UpdateStrategy: strategy,
+
+		Storage: s,
+	}
+
+	return &REST{Etcd: store, access: strategy}
+}
+
+// Create stores newImage and, if it is a manifest list or OCI image index, additionally expands
+// it into one Image per referenced platform (each linked back to newImage's digest through
+// ManifestListDigest) so each platform's own manifest can be fetched and parsed like any other
+// pushed Image.
+func (r *REST) Create(ctx kapi.Context, obj runtime.Object) (runtime.Object, error) {
+	newImage := obj.(*api.Image)
+	created, err := r.Etcd.Create(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if !image.IsManifestList(newImage) {
+		return created, nil
+	}
+
+	platforms, err := api.ExpandManifestList(newImage.Name, newImage.DockerImageReference, []byte(newImage.DockerImageManifest))
+	if err != nil {
+		return nil, fmt.Errorf("error expanding manifest list %q: %v", newImage.Name, err)
+	}
+	for _, platformImage := range platforms {
+		if _, err := r.Etcd.Create(ctx, platformImage); err != nil && !kapierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("error creating per-platform image %q for manifest list %q: %v", platformImage.Name, newImage.Name, err)
+		}
+	}
+	return created, nil
+}
+
+// Get fetches an Image by name and strips its manifest/layers if ctx's user cannot access layers
+// in any namespace that references it.
+func (r *REST) Get(ctx kapi.Context, name string) (runtime.Object, error) {
+	obj, err := r.Etcd.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.access.Get(ctx, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// List fetches Images matching options and strips the manifest/layers of any that ctx's user
+// cannot access layers for. A SubjectAccessReview error fails closed, same as Get: the item's
+// layer data is stripped rather than left intact, so a SAR backend outage cannot leak a private
+// manifest through List.
+func (r *REST) List(ctx kapi.Context, options *kapi.ListOptions) (runtime.Object, error) {
+	obj, err := r.Etcd.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := obj.(*api.ImageList)
+	if !ok {
+		return nil, fmt.Errorf("unexpected list type %T from image storage", obj)
+	}
+	for i := range list.Items {
+		if err := r.access.Get(ctx, &list.Items[i]); err != nil {
+			util.HandleError(fmt.Errorf("Unable to check layer access for image %q: %v", list.Items[i].Name, err))
+			image.StripLayerData(&list.Items[i])
+		}
+	}
+	return list, nil
+}
+
+// Export implements rest.Exporter, applying the same layer-access gating as Get/List to an
+// Image already fetched by the generic export path before it is returned to `oc export`.
+func (r *REST) Export(ctx kapi.Context, obj runtime.Object, exact bool) error {
+	return r.access.Export(ctx, obj, exact)
+}
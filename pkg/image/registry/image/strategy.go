@@ -4,10 +4,12 @@ import (
 	"fmt"
 
 	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
 	"k8s.io/kubernetes/pkg/fields"
 	"k8s.io/kubernetes/pkg/labels"
 	"k8s.io/kubernetes/pkg/registry/generic"
 	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/storage"
 	"k8s.io/kubernetes/pkg/util"
 	"k8s.io/kubernetes/pkg/util/validation/field"
 
@@ -19,11 +21,46 @@ import (
 type imageStrategy struct {
 	runtime.ObjectTyper
 	kapi.NameGenerator
+
+	// blobs fetches the config blob a schema 2 or OCI manifest references so PrepareForCreate can
+	// populate DockerImageMetadata from it; it is nil only for the package-level default Strategy,
+	// in which case schema 2/OCI images get layers but an empty DockerImageMetadata.
+	blobs api.ConfigBlobFetcher
+
+	// trustStore verifies signed (PGP/PKCS7) signatures embedded on create; it is nil unless the
+	// master is configured with a set of trusted keys/CAs. While it is nil, those signatures are
+	// recorded as untrusted rather than verified.
+	trustStore api.TrustStore
+
+	// streams and sar gate access to DockerImageManifest/DockerImageLayers: a caller may read
+	// them only if they can `get imagestreams/layers` in at least one namespace whose
+	// ImageStream currently tags this image's digest. Both are nil unless the master is
+	// configured with an authorizer. While either is nil, layer access is unrestricted (pre-SAR
+	// behavior) for compatibility with clusters that have not opted in.
+	streams api.ImageStreamLister
+	sar     api.SubjectAccessChecker
 }
 
-// Strategy is the default logic that applies when creating and updating
-// Image objects via the REST API.
-var Strategy = imageStrategy{kapi.Scheme, kapi.SimpleNameGenerator}
+// Strategy is the default logic that applies when creating and updating Image objects via the
+// REST API. It has no config blob fetcher, trust store, or authorizer configured; NewStrategy is
+// what the master actually constructs its RESTStorage with.
+var Strategy = imageStrategy{ObjectTyper: kapi.Scheme, NameGenerator: kapi.SimpleNameGenerator}
+
+// NewStrategy returns an imageStrategy that fetches config blobs through blobs to populate
+// DockerImageMetadata for schema 2/OCI images, cross-checks signatures embedded on create against
+// trustStore (resolving PGP/PKCS7 envelopes using the master's configured trusted keys/CAs), and,
+// when streams and sar are non-nil, gates reads of DockerImageManifest and DockerImageLayers
+// behind a SubjectAccessReview against every ImageStream tagging the image.
+func NewStrategy(blobs api.ConfigBlobFetcher, trustStore api.TrustStore, streams api.ImageStreamLister, sar api.SubjectAccessChecker) imageStrategy {
+	return imageStrategy{
+		ObjectTyper:   kapi.Scheme,
+		NameGenerator: kapi.SimpleNameGenerator,
+		blobs:         blobs,
+		trustStore:    trustStore,
+		streams:       streams,
+		sar:           sar,
+	}
+}
 
 // NamespaceScoped is false for images.
 func (imageStrategy) NamespaceScoped() bool {
@@ -32,12 +69,131 @@ func (imageStrategy) NamespaceScoped() bool {
 
 // PrepareForCreate clears fields that are not allowed to be set by end users on creation.
 // It extracts the latest information from the manifest (if available) and sets that onto the object.
-func (imageStrategy) PrepareForCreate(obj runtime.Object) {
+// The manifest's media type determines how it is parsed: legacy schema 1, schema 2, or OCI image
+// manifests each populate DockerImageMetadata and DockerImageLayers differently, and manifest lists
+// carry no metadata of their own (storage expands them into one Image per platform instead).
+func (s imageStrategy) PrepareForCreate(obj runtime.Object) {
 	newImage := obj.(*api.Image)
 	// ignore errors, change in place
-	if err := api.ImageWithMetadata(newImage); err != nil {
+	if err := api.ImageWithMetadataAndBlobs(newImage, s.blobs); err != nil {
 		util.HandleError(fmt.Errorf("Unable to update image metadata for %q: %v", newImage.Name, err))
 	}
+	s.verifySignatures(newImage)
+}
+
+// verifySignatures cross-checks every signature embedded on newImage against s.trustStore and
+// records the result as a Trusted condition, so that admission and `oc` can show a signature's
+// trust state without re-verifying it on every read. A signature whose envelope cannot be
+// verified (no trust store configured, unknown key, claims mismatch) is recorded as untrusted
+// rather than dropped, so revocation of a previously-trusted key is visible on the next push.
+func (s imageStrategy) verifySignatures(image *api.Image) {
+	now := unversioned.Now()
+	for i := range image.Signatures {
+		signature := &image.Signatures[i]
+		condition := api.SignatureCondition{
+			Type:               api.SignatureTrusted,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+		}
+		dockerReference, claims, err := api.VerifySignature(image, signature, s.trustStore)
+		if err != nil {
+			condition.Status = kapi.ConditionFalse
+			condition.Reason = "VerificationFailed"
+			condition.Message = err.Error()
+		} else {
+			condition.Status = kapi.ConditionTrue
+			signature.ImageIdentity = dockerReference
+			signature.SignedClaims = claims
+		}
+		signature.Conditions = []api.SignatureCondition{condition}
+	}
+}
+
+// imageStreamsLayersResource is the resource a caller must `get` (to read layers) or `update`
+// (to push a new tag) against an ImageStream that references a digest.
+const imageStreamsLayersResource = "imagestreams/layers"
+
+// canAccessLayers reports whether the user on ctx may read image's manifest and layers: true if
+// no authorizer is configured (preserving pre-SAR behavior), if no ImageStream currently tags
+// image's digest, or if the user can `get imagestreams/layers` in at least one namespace whose
+// ImageStream does. The no-references case is intentionally permissive rather than restrictive:
+// there is nothing to check access against, so this matches
+// pkg/image/admission/layeraccess.Admit, which lets the integrated registry's own push
+// authorization stand as the only gate for a digest no ImageStream references yet. Denying here
+// instead would strip manifest/layers from every not-yet-tagged image for every caller, including
+// the integrated registry and pruner.
+func (s imageStrategy) canAccessLayers(ctx kapi.Context, image *api.Image) (bool, error) {
+	if s.streams == nil || s.sar == nil {
+		return true, nil
+	}
+
+	refs, err := s.streams.ImageStreamsForImage(image.Name)
+	if err != nil {
+		return false, err
+	}
+	if len(refs) == 0 {
+		return true, nil
+	}
+
+	for _, ref := range refs {
+		allowed, err := s.sar.Allowed(ctx, ref.Namespace, "get", imageStreamsLayersResource)
+		if err != nil {
+			util.HandleError(fmt.Errorf("Unable to check imagestreams/layers access for %q in %q: %v", image.Name, ref.Namespace, err))
+			continue
+		}
+		if allowed {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// StripLayerData clears the fields that expose an image's content, leaving only the metadata
+// needed to know the Image exists, so that a caller without layer access still sees it listed
+// but cannot read what it contains. It is exported so callers that must fail closed on a
+// per-item basis (the etcd RESTStorage's List, on a SubjectAccessReview error) can apply it
+// directly rather than going through the all-or-nothing Get/Export.
+func StripLayerData(image *api.Image) {
+	image.DockerImageManifest = ""
+	image.DockerImageManifestMediaType = ""
+	image.DockerImageLayers = nil
+	image.DockerImageMetadata = api.DockerImage{}
+}
+
+// LayerAccessStrategy is implemented by imageStrategy and consulted by the RESTStorage in
+// ./etcd on every read (Get, List, Export) to strip an Image's manifest and layers from a caller
+// who cannot access layers in any namespace that references its digest.
+type LayerAccessStrategy interface {
+	Get(ctx kapi.Context, obj runtime.Object) error
+	Export(ctx kapi.Context, obj runtime.Object, exact bool) error
+}
+
+// Get strips DockerImageManifest and DockerImageLayers from image when the user on ctx cannot
+// access layers in any namespace that references its digest.
+func (s imageStrategy) Get(ctx kapi.Context, obj runtime.Object) error {
+	image := obj.(*api.Image)
+	allowed, err := s.canAccessLayers(ctx, image)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		StripLayerData(image)
+	}
+	return nil
+}
+
+// Export applies the same layer-access gating as Get to an Image destined for `oc export`.
+func (s imageStrategy) Export(ctx kapi.Context, obj runtime.Object, exact bool) error {
+	return s.Get(ctx, obj)
+}
+
+// IsManifestList returns true if the image's manifest is a Docker manifest list or OCI image
+// index. Callers in the registry storage layer use this to expand the image into one Image per
+// referenced platform, each named after its own manifest digest and linked back to this image's
+// digest as its parent, in addition to storing the list itself as an Image.
+func IsManifestList(image *api.Image) bool {
+	return api.IsManifestList(image.DockerImageManifestMediaType)
 }
 
 // Validate validates a new image.
@@ -61,7 +217,7 @@ func (imageStrategy) Canonicalize(obj runtime.Object) {
 
 // PrepareForUpdate clears fields that are not allowed to be set by end users on update.
 // It extracts the latest info from the manifest and sets that on the object.
-func (imageStrategy) PrepareForUpdate(obj, old runtime.Object) {
+func (s imageStrategy) PrepareForUpdate(obj, old runtime.Object) {
 	newImage := obj.(*api.Image)
 	oldImage := old.(*api.Image)
 
@@ -69,10 +225,13 @@ func (imageStrategy) PrepareForUpdate(obj, old runtime.Object) {
 	newImage.DockerImageReference = oldImage.DockerImageReference
 	newImage.DockerImageMetadata = oldImage.DockerImageMetadata
 	newImage.DockerImageManifest = oldImage.DockerImageManifest
+	newImage.DockerImageManifestMediaType = oldImage.DockerImageManifestMediaType
 	newImage.DockerImageMetadataVersion = oldImage.DockerImageMetadataVersion
 	newImage.DockerImageLayers = oldImage.DockerImageLayers
+	// signatures are only added through the imagesignature subresource
+	newImage.Signatures = oldImage.Signatures
 
-	if err := api.ImageWithMetadata(newImage); err != nil {
+	if err := api.ImageWithMetadataAndBlobs(newImage, s.blobs); err != nil {
 		util.HandleError(fmt.Errorf("Unable to update image metadata for %q: %v", newImage.Name, err))
 	}
 }
@@ -82,14 +241,45 @@ func (imageStrategy) ValidateUpdate(ctx kapi.Context, obj, old runtime.Object) f
 	return validation.ValidateImageUpdate(old.(*api.Image), obj.(*api.Image))
 }
 
-// MatchImage returns a generic matcher for a given label and field selector.
+// MatchImage returns a generic.Matcher for a given label and field selector, for use as the
+// etcd generic registry's PredicateFunc. The underlying storage.SelectionPredicate also
+// implements the etcd generic registry's indexed-lookup extension: its IndexFields list the
+// single-valued Image fields (api.IndexedImageFields) that the storage layer maintains a
+// secondary etcd index on, so a selector naming one of them (e.g. `dockerImageReference=...`)
+// becomes a point lookup instead of a full list-and-decode scan. "which images contain this
+// layer digest" queries go through the separate, multi-valued api.LayersDigestIndexField index
+// built from ImageLayerDigestIndexFunc rather than through field selection.
 func MatchImage(label labels.Selector, field fields.Selector) generic.Matcher {
-	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
-		image, ok := obj.(*api.Image)
-		if !ok {
-			return false, fmt.Errorf("not an image")
-		}
-		fields := api.ImageToSelectableFields(image)
-		return label.Matches(labels.Set(image.Labels)) && field.Matches(fields), nil
-	})
+	return imageSelectionPredicate(label, field)
+}
+
+// imageSelectionPredicate builds the storage.SelectionPredicate shared by MatchImage (as a
+// generic.Matcher) and the etcd RESTStorage's Indexers wiring.
+func imageSelectionPredicate(label labels.Selector, field fields.Selector) storage.SelectionPredicate {
+	return storage.SelectionPredicate{
+		Label:       label,
+		Field:       field,
+		GetAttrs:    ImageAttrs,
+		IndexFields: api.IndexedImageFields,
+	}
+}
+
+// ImageAttrs returns the label and field sets used to match and index an Image.
+func ImageAttrs(obj runtime.Object) (labels.Set, fields.Set, error) {
+	image, ok := obj.(*api.Image)
+	if !ok {
+		return nil, nil, fmt.Errorf("not an image")
+	}
+	return labels.Set(image.Labels), api.ImageToSelectableFields(image), nil
+}
+
+// ImageLayerDigestIndexFunc returns the layer digests referenced by obj, for use as the
+// IndexFunc that builds the secondary, multi-valued api.LayersDigestIndexField index the pruner
+// and GC use to answer "which images contain this layer digest" without a list scan.
+func ImageLayerDigestIndexFunc(obj runtime.Object) ([]string, error) {
+	image, ok := obj.(*api.Image)
+	if !ok {
+		return nil, fmt.Errorf("not an image")
+	}
+	return api.ImageLayerDigests(image), nil
 }
@@ -0,0 +1,42 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+func TestImageAttrsReturnsIndexedFields(t *testing.T) {
+	img := &api.Image{DockerImageReference: "registry.example.com/foo/bar@sha256:abc"}
+	img.Name = "sha256:abc"
+
+	_, fieldSet, err := ImageAttrs(img)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fieldSet.Get("metadata.name"); got != img.Name {
+		t.Errorf("got metadata.name %q, want %q", got, img.Name)
+	}
+	if got := fieldSet.Get("dockerImageReference"); got != img.DockerImageReference {
+		t.Errorf("got dockerImageReference %q, want %q", got, img.DockerImageReference)
+	}
+}
+
+func TestImageLayerDigestIndexFunc(t *testing.T) {
+	img := &api.Image{
+		DockerImageLayers: []api.ImageLayer{
+			{Name: "sha256:one"},
+			{Name: "sha256:two"},
+		},
+	}
+
+	digests, err := ImageLayerDigestIndexFunc(img)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"sha256:one", "sha256:two"}
+	if !reflect.DeepEqual(digests, want) {
+		t.Errorf("got digests %v, want %v", digests, want)
+	}
+}
@@ -0,0 +1,120 @@
+package image
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+type fakeBlobFetcher map[string][]byte
+
+func (f fakeBlobFetcher) Get(dockerImageReference, digest string) ([]byte, error) {
+	return f[digest], nil
+}
+
+type fakeImageStreamLister []api.ImageStreamReference
+
+func (f fakeImageStreamLister) ImageStreamsForImage(imageName string) ([]api.ImageStreamReference, error) {
+	return []api.ImageStreamReference(f), nil
+}
+
+type fakeSubjectAccessChecker bool
+
+func (f fakeSubjectAccessChecker) Allowed(ctx kapi.Context, namespace, verb, resource string) (bool, error) {
+	return bool(f), nil
+}
+
+func TestGetStripsLayerDataWithoutAccess(t *testing.T) {
+	streams := fakeImageStreamLister{{Namespace: "ns"}}
+	strategy := NewStrategy(nil, nil, streams, fakeSubjectAccessChecker(false))
+
+	image := &api.Image{
+		DockerImageManifest:          "raw-manifest",
+		DockerImageManifestMediaType: api.DockerImageManifestSchema2MediaType,
+		DockerImageLayers:            []api.ImageLayer{{Name: "sha256:one"}},
+	}
+
+	if err := strategy.Get(kapi.NewContext(), image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.DockerImageManifest != "" || image.DockerImageLayers != nil {
+		t.Errorf("expected manifest/layers to be stripped, got %+v", image)
+	}
+}
+
+func TestGetLeavesLayerDataWithAccess(t *testing.T) {
+	streams := fakeImageStreamLister{{Namespace: "ns"}}
+	strategy := NewStrategy(nil, nil, streams, fakeSubjectAccessChecker(true))
+
+	image := &api.Image{
+		DockerImageManifest:          "raw-manifest",
+		DockerImageManifestMediaType: api.DockerImageManifestSchema2MediaType,
+		DockerImageLayers:            []api.ImageLayer{{Name: "sha256:one"}},
+	}
+
+	if err := strategy.Get(kapi.NewContext(), image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.DockerImageManifest == "" || image.DockerImageLayers == nil {
+		t.Errorf("expected manifest/layers to be left intact, got %+v", image)
+	}
+}
+
+func TestGetLeavesLayerDataWhenNoStreamReferencesImage(t *testing.T) {
+	streams := fakeImageStreamLister{}
+	strategy := NewStrategy(nil, nil, streams, fakeSubjectAccessChecker(false))
+
+	image := &api.Image{
+		DockerImageManifest:          "raw-manifest",
+		DockerImageManifestMediaType: api.DockerImageManifestSchema2MediaType,
+		DockerImageLayers:            []api.ImageLayer{{Name: "sha256:one"}},
+	}
+
+	if err := strategy.Get(kapi.NewContext(), image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.DockerImageManifest == "" || image.DockerImageLayers == nil {
+		t.Errorf("expected manifest/layers to be left intact for an untagged image, got %+v", image)
+	}
+}
+
+func TestGetAllowsLayerDataWhenNoAuthorizerConfigured(t *testing.T) {
+	strategy := NewStrategy(nil, nil, nil, nil)
+
+	image := &api.Image{
+		DockerImageManifest: "raw-manifest",
+		DockerImageLayers:   []api.ImageLayer{{Name: "sha256:one"}},
+	}
+
+	if err := strategy.Get(kapi.NewContext(), image); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image.DockerImageManifest == "" {
+		t.Errorf("expected manifest to be left intact when no authorizer is configured, got %+v", image)
+	}
+}
+
+func TestPrepareForCreatePopulatesMetadataFromConfigBlob(t *testing.T) {
+	const configDigest = "sha256:config"
+	blobs := fakeBlobFetcher{
+		configDigest: []byte(`{"Architecture":"amd64"}`),
+	}
+
+	strategy := NewStrategy(blobs, nil, nil, nil)
+
+	image := &api.Image{
+		DockerImageManifestMediaType: api.DockerImageManifestSchema2MediaType,
+		DockerImageManifest: `{"schemaVersion":2,"mediaType":"` + api.DockerImageManifestSchema2MediaType + `",` +
+			`"config":{"digest":"` + configDigest + `"},"layers":[]}`,
+	}
+
+	strategy.PrepareForCreate(image)
+
+	want := api.DockerImage{Architecture: "amd64"}
+	if !reflect.DeepEqual(image.DockerImageMetadata, want) {
+		t.Errorf("got DockerImageMetadata %+v, want %+v", image.DockerImageMetadata, want)
+	}
+}
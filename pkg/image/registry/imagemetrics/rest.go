@@ -0,0 +1,55 @@
+package imagemetrics
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/metrics"
+	"k8s.io/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// ImageGetter is the subset of the image registry REST's Get that the metrics endpoint needs to
+// confirm the requested Image exists before reporting counters for it.
+type ImageGetter interface {
+	Get(ctx kapi.Context, name string) (runtime.Object, error)
+}
+
+// REST implements the read-only imagemetrics subresource, aggregating counters maintained by
+// the registry middleware and the image pruner into a metrics.ImageMetrics for a single Image.
+type REST struct {
+	images   ImageGetter
+	counters api.Counters
+}
+
+// NewREST returns a RESTStorage backing the "images/metrics" subresource.
+func NewREST(images ImageGetter, counters api.Counters) *REST {
+	return &REST{images: images, counters: counters}
+}
+
+// New returns a new ImageMetrics; this subresource is read-only (Get only), so it exists solely
+// to tell the generic REST handler what kind to decode and encode.
+func (r *REST) New() runtime.Object {
+	return &metrics.ImageMetrics{}
+}
+
+// Get retrieves an ImageMetrics for the Image named name.
+func (r *REST) Get(ctx kapi.Context, name string) (runtime.Object, error) {
+	if _, err := r.images.Get(ctx, name); err != nil {
+		return nil, err
+	}
+
+	counters, ok := r.counters.ImageCounters(name)
+	if !ok {
+		return nil, kerrors.NewNotFound("ImageMetrics", name)
+	}
+
+	return &metrics.ImageMetrics{
+		ObjectMeta:       kapi.ObjectMeta{Name: name},
+		LayerBytesStored: counters.LayerBytesStored,
+		UniqueBlobBytes:  counters.UniqueBlobBytes,
+		PullsPerMinute:   counters.PullsPerMinute,
+		PushesPerMinute:  counters.PushesPerMinute,
+		LastPullTime:     counters.LastPullTime,
+	}, nil
+}
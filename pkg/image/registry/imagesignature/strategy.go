@@ -0,0 +1,56 @@
+package imagesignature
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/runtime"
+	"k8s.io/kubernetes/pkg/util/validation/field"
+
+	"github.com/openshift/origin/pkg/image/api"
+	"github.com/openshift/origin/pkg/image/api/validation"
+)
+
+// strategy implements behavior for ImageSignatures, the subresource used to attach a signature
+// to an existing Image by digest.
+type strategy struct {
+	runtime.ObjectTyper
+	kapi.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating ImageSignature objects via the REST
+// API. Trust verification happens in the parent imageStrategy (see
+// pkg/image/registry/image.imageStrategy.verifySignatures), which runs when an Image is created
+// with signatures embedded, and in the periodic resync of
+// pkg/image/controller.SignatureController; this strategy computes no trust conditions.
+var Strategy = strategy{kapi.Scheme, kapi.SimpleNameGenerator}
+
+// NamespaceScoped is false; signatures share the cluster scope of the Image they attach to.
+func (strategy) NamespaceScoped() bool {
+	return false
+}
+
+// PrepareForCreate clears fields that are not allowed to be set by end users on creation.
+// Trust conditions are computed by the parent Image's strategy, not here.
+func (strategy) PrepareForCreate(obj runtime.Object) {
+	signature := obj.(*api.ImageSignature)
+	signature.Conditions = nil
+	signature.ImageIdentity = ""
+	signature.SignedClaims = nil
+}
+
+// Validate validates a new ImageSignature.
+func (strategy) Validate(ctx kapi.Context, obj runtime.Object) field.ErrorList {
+	signature := obj.(*api.ImageSignature)
+	return validation.ValidateImageSignature(signature)
+}
+
+// Canonicalize normalizes the object after validation.
+func (strategy) Canonicalize(obj runtime.Object) {}
+
+// AllowCreateOnUpdate is false; signatures are immutable once created.
+func (strategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (strategy) AllowUnconditionalUpdate() bool {
+	return false
+}
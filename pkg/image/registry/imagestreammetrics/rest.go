@@ -0,0 +1,61 @@
+package imagestreammetrics
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	kerrors "k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/apis/metrics"
+	"k8s.io/kubernetes/pkg/runtime"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// ImageStreamGetter is the subset of the imagestream registry REST's Get that the metrics
+// endpoint needs to confirm the requested ImageStream exists before reporting counters for it.
+type ImageStreamGetter interface {
+	Get(ctx kapi.Context, name string) (runtime.Object, error)
+}
+
+// REST implements the read-only imagestreams/metrics subresource, aggregating counters across
+// every tag of an ImageStream into a single metrics.ImageStreamMetrics.
+type REST struct {
+	imageStreams ImageStreamGetter
+	counters     api.Counters
+}
+
+// NewREST returns a RESTStorage backing the "imagestreams/metrics" subresource.
+func NewREST(imageStreams ImageStreamGetter, counters api.Counters) *REST {
+	return &REST{imageStreams: imageStreams, counters: counters}
+}
+
+// New returns a new ImageStreamMetrics; this subresource is read-only (Get only), so it exists
+// solely to tell the generic REST handler what kind to decode and encode.
+func (r *REST) New() runtime.Object {
+	return &metrics.ImageStreamMetrics{}
+}
+
+// Get retrieves an ImageStreamMetrics for the ImageStream named name in the request context's
+// namespace.
+func (r *REST) Get(ctx kapi.Context, name string) (runtime.Object, error) {
+	namespace, ok := kapi.NamespaceFrom(ctx)
+	if !ok {
+		return nil, kerrors.NewBadRequest("namespace is required")
+	}
+
+	if _, err := r.imageStreams.Get(ctx, name); err != nil {
+		return nil, err
+	}
+
+	counters, ok := r.counters.ImageStreamCounters(namespace, name)
+	if !ok {
+		return nil, kerrors.NewNotFound("ImageStreamMetrics", name)
+	}
+
+	return &metrics.ImageStreamMetrics{
+		ObjectMeta:       kapi.ObjectMeta{Name: name, Namespace: namespace},
+		LayerBytesStored: counters.LayerBytesStored,
+		UniqueBlobBytes:  counters.UniqueBlobBytes,
+		PullsPerMinute:   counters.PullsPerMinute,
+		PushesPerMinute:  counters.PushesPerMinute,
+		LastPullTime:     counters.LastPullTime,
+	}, nil
+}